@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/spikeekips/sebak-hot-body/hotbody"
+)
+
+var (
+	compareCmd                  *cobra.Command
+	flagCompareOutput           string = "table"
+	flagFailOnOPSRegression     string
+	flagFailOnP99Regression     string
+)
+
+func init() {
+	compareCmd = &cobra.Command{
+		Use:   "compare <result log> <result log> [<result log> ...]",
+		Short: "Diff two or more result logs into a regression report",
+		Run: func(c *cobra.Command, args []string) {
+			parseCompareFlags(args)
+
+			runCompare(args)
+		},
+	}
+
+	compareCmd.Flags().StringVar(&flagLogLevel, "log-level", flagLogLevel, "log level, {crit, error, warn, info, debug}")
+	compareCmd.Flags().StringVar(&flagLogFormat, "log-format", flagLogFormat, "log format, {terminal, json}")
+	compareCmd.Flags().StringVar(&flagLog, "log", flagLog, "set log file")
+	compareCmd.Flags().DurationVar(&flagHistogramMax, "histogram-max", flagHistogramMax, "upper bound of the latency histogram's buckets")
+	compareCmd.Flags().StringVar(&flagCompareOutput, "output", flagCompareOutput, "output format, {table, json}")
+	compareCmd.Flags().StringVar(&flagFailOnOPSRegression, "fail-on-ops-regression", flagFailOnOPSRegression, "exit non-zero if real OPS drops by at least this, e.g. 5%")
+	compareCmd.Flags().StringVar(&flagFailOnP99Regression, "fail-on-p99-regression", flagFailOnP99Regression, "exit non-zero if p99 latency grows by at least this, e.g. 20%")
+
+	rootCmd.AddCommand(compareCmd)
+}
+
+func parseCompareFlags(args []string) {
+	setLogging()
+
+	if len(args) < 2 {
+		printError(compareCmd, fmt.Errorf("at least 2 <result log> are required"))
+	}
+}
+
+// parsePercentThreshold parses a flag value like "5%" or "0.05" into the
+// 0-1 range; an empty string disables the threshold (returns 0).
+func parsePercentThreshold(s string) float64 {
+	if len(s) < 1 {
+		return 0
+	}
+
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			printError(compareCmd, fmt.Errorf("invalid threshold %q; %v", s, err))
+		}
+		return v / 100
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		printError(compareCmd, fmt.Errorf("invalid threshold %q; %v", s, err))
+	}
+	return v
+}
+
+func runCompare(paths []string) {
+	reports := make([]hotbody.ResultReport, len(paths))
+	for i, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			printError(compareCmd, fmt.Errorf("failed to open %q; %v", path, err))
+		}
+
+		report, err := buildResultReport(f, flagHistogramMax, nil)
+		f.Close()
+		if err != nil {
+			printError(compareCmd, fmt.Errorf("failed to read %q; %v", path, err))
+		}
+
+		reports[i] = report
+	}
+
+	opsThreshold := parsePercentThreshold(flagFailOnOPSRegression)
+	p99Threshold := parsePercentThreshold(flagFailOnP99Regression)
+
+	baseline := reports[0]
+	var deltas []hotbody.ResultDelta
+	var regressed bool
+	for i := 1; i < len(reports); i++ {
+		delta := hotbody.CompareResults(baseline, reports[i])
+		deltas = append(deltas, delta)
+
+		if delta.Regressed(opsThreshold, p99Threshold) {
+			regressed = true
+		}
+	}
+
+	if flagCompareOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(deltas); err != nil {
+			printError(compareCmd, fmt.Errorf("failed to encode comparison as json; %v", err))
+		}
+	} else {
+		renderCompareTable(paths, baseline, reports[1:], deltas)
+	}
+
+	if regressed {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func renderCompareTable(paths []string, baseline hotbody.ResultReport, candidates []hotbody.ResultReport, deltas []hotbody.ResultDelta) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"run", "real OPS", "error rate", "p99", "ops Δ", "p99 Δ"})
+
+	table.Append([]string{
+		paths[0],
+		fmt.Sprintf("%.2f", baseline.RealOPS),
+		fmt.Sprintf("%.5f％", baseline.ErrorRate*100),
+		baseline.Latency.P99.String(),
+		"-",
+		"-",
+	})
+
+	for i, candidate := range candidates {
+		delta := deltas[i]
+		table.Append([]string{
+			paths[i+1],
+			fmt.Sprintf("%.2f", candidate.RealOPS),
+			fmt.Sprintf("%.5f％", candidate.ErrorRate*100),
+			candidate.Latency.P99.String(),
+			fmt.Sprintf("%+.2f％", delta.OPSChange*100),
+			fmt.Sprintf("%+.2f％", delta.P99Change*100),
+		})
+	}
+
+	table.Render()
+}
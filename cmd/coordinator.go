@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/spikeekips/sebak-hot-body/hotbody"
+)
+
+var (
+	coordinatorCmd        *cobra.Command
+	flagCoordinatorListen string = ":13221"
+	flagAccountsFile      string
+	flagNumWorkers        int = 1
+	flagTotalTargetOPS    int = 100
+)
+
+func init() {
+	coordinatorCmd = &cobra.Command{
+		Use:   "coordinator <accounts file> <result log>",
+		Short: "Distribute account ranges across hotbody workers and merge their results",
+		Run: func(c *cobra.Command, args []string) {
+			parseCoordinatorFlags(args)
+
+			runCoordinator(args)
+		},
+	}
+
+	coordinatorCmd.Flags().StringVar(&flagLogLevel, "log-level", flagLogLevel, "log level, {crit, error, warn, info, debug}")
+	coordinatorCmd.Flags().StringVar(&flagLogFormat, "log-format", flagLogFormat, "log format, {terminal, json}")
+	coordinatorCmd.Flags().StringVar(&flagLog, "log", flagLog, "set log file")
+	coordinatorCmd.Flags().StringVar(&flagCoordinatorListen, "listen", flagCoordinatorListen, "address workers register against")
+	coordinatorCmd.Flags().IntVar(&flagNumWorkers, "workers", flagNumWorkers, "number of workers expected to register before the run starts")
+	coordinatorCmd.Flags().IntVar(&flagTotalTargetOPS, "target-ops", flagTotalTargetOPS, "combined OPS target split evenly across workers")
+
+	rootCmd.AddCommand(coordinatorCmd)
+}
+
+func parseCoordinatorFlags(args []string) {
+	setLogging()
+
+	if len(args) < 2 {
+		printError(coordinatorCmd, fmt.Errorf("<accounts file> and <result log> are required"))
+	}
+	flagAccountsFile = args[0]
+	flagResultOutput = args[1]
+
+	if flagNumWorkers < 1 {
+		printError(coordinatorCmd, fmt.Errorf("--workers must be at least 1, got %d", flagNumWorkers))
+	}
+}
+
+// writeResultHead writes the "config" and "started" records a merged
+// result log needs as its first lines, in the same shape a single-box
+// hotter run emits, so `result`/`compare` can consume a coordinator's
+// output unchanged.
+func writeResultHead(w *os.File, accounts []string) error {
+	var initAccount string
+	if len(accounts) > 0 {
+		initAccount = accounts[0]
+	}
+
+	config := struct {
+		Type   string              `json:"type"`
+		Config hotbody.HotterConfig `json:"config"`
+	}{
+		Type: "config",
+		Config: hotbody.HotterConfig{
+			Type:        "config",
+			Time:        time.Now(),
+			T:           flagNumWorkers,
+			InitAccount: initAccount,
+			Operations:  1,
+		},
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	started := struct {
+		Type string `json:"type"`
+		Time string `json:"time"`
+	}{
+		Type: "started",
+		Time: FormatISO8601(time.Now()),
+	}
+
+	b, err = json.Marshal(started)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+func loadAccounts(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var accounts []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if len(sc.Text()) > 0 {
+			accounts = append(accounts, sc.Text())
+		}
+	}
+
+	return accounts, sc.Err()
+}
+
+func runCoordinator(args []string) {
+	accounts, err := loadAccounts(flagAccountsFile)
+	if err != nil {
+		printError(coordinatorCmd, fmt.Errorf("failed to load <accounts file>; %v", err))
+	}
+
+	out, err := os.Create(flagResultOutput)
+	if err != nil {
+		printError(coordinatorCmd, fmt.Errorf("failed to create <result log>; %v", err))
+	}
+	defer out.Close()
+
+	if err := writeResultHead(out, accounts); err != nil {
+		printError(coordinatorCmd, fmt.Errorf("failed to write <result log> head; %v", err))
+	}
+
+	coordinator, err := hotbody.NewCoordinator(accounts, flagNumWorkers, flagTotalTargetOPS, out)
+	if err != nil {
+		printError(coordinatorCmd, fmt.Errorf("failed to start coordinator; %v", err))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
+		var reg hotbody.WorkerRegistration
+		if err := json.NewDecoder(r.Body).Decode(&reg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		assignment, err := coordinator.Register(reg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		log.Info("worker registered", "id", reg.ID, "accounts", assignment.Accounts, "target-ops", assignment.TargetOPS)
+		json.NewEncoder(w).Encode(assignment)
+	})
+
+	mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		var records []json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := coordinator.Ingest(records); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	log.Info("coordinator listening", "addr", flagCoordinatorListen, "workers", flagNumWorkers)
+	if err := http.ListenAndServe(flagCoordinatorListen, mux); err != nil {
+		printError(coordinatorCmd, fmt.Errorf("coordinator stopped; %v", err))
+	}
+}
@@ -4,9 +4,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"math"
 	"os"
-	"sort"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
@@ -18,12 +16,34 @@ import (
 )
 
 var (
-	resultCmd    *cobra.Command
-	resultOutput *os.File
-	started      time.Time
-	ended        time.Time
+	resultCmd          *cobra.Command
+	resultOutput       *os.File
+	flagMetricsListen  string
+	flagOutput         string = "table"
+	flagHistogramMax   time.Duration = 10 * time.Second
+	flagReplay         string
 )
 
+// replaySender is how `result --replay` actually issues the replayed
+// payments; it defaults to a dry-run sender that only logs, since this
+// package does not carry the hotter's own sebak client. A build wiring
+// that client in replaces this with one that dials the live network.
+var replaySender = func(hotbody.HotterConfig) hotbody.Sender { return logOnlySender{} }
+
+// resetAccounts restores every account in the scenario to the balance it
+// held when the scenario was captured, via the same create-accounts
+// machinery a fresh run uses. For the same reason as replaySender, the
+// default here is a no-op that resets nothing; a build wiring the client
+// in replaces it.
+var resetAccounts = func(accounts []string) error { return nil }
+
+type logOnlySender struct{}
+
+func (logOnlySender) Send(op hotbody.ScenarioOp) error {
+	log.Debug("replay (dry-run)", "source", op.Source, "target", op.Target, "amount", op.Amount, "at", op.At)
+	return nil
+}
+
 func init() {
 	resultCmd = &cobra.Command{
 		Use:   "result <result log>",
@@ -39,6 +59,10 @@ func init() {
 	resultCmd.Flags().StringVar(&flagLogFormat, "log-format", flagLogFormat, "log format, {terminal, json}")
 	resultCmd.Flags().StringVar(&flagLog, "log", flagLog, "set log file")
 	resultCmd.Flags().BoolVar(&flagBrief, "brief", flagBrief, "show only result")
+	resultCmd.Flags().StringVar(&flagMetricsListen, "metrics-listen", flagMetricsListen, "serve live metrics on this address while replaying, e.g. :9090")
+	resultCmd.Flags().StringVar(&flagOutput, "output", flagOutput, "output format, {table, json}")
+	resultCmd.Flags().DurationVar(&flagHistogramMax, "histogram-max", flagHistogramMax, "upper bound of the latency histogram's buckets")
+	resultCmd.Flags().StringVar(&flagReplay, "replay", flagReplay, "replay a captured scenario file instead of reporting on <result log>")
 
 	rootCmd.AddCommand(resultCmd)
 }
@@ -48,6 +72,10 @@ func parseResultFlags(args []string) {
 
 	setLogging()
 
+	if len(flagReplay) > 0 {
+		return
+	}
+
 	if len(args) < 1 {
 		printError(resultCmd, fmt.Errorf("<result log> is missing"))
 	}
@@ -67,7 +95,11 @@ func parseResultFlags(args []string) {
 	log.Debug("parsed flags:", parsedFlags...)
 }
 
-func loadLine(l string) (record hotbody.Record, err error) {
+// loadLine parses a single result log line. "started" lines report their
+// timestamp through started rather than via the returned Record, so each
+// result log's start time stays local to that log instead of leaking
+// across files a caller (e.g. `compare`) parses in the same process.
+func loadLine(l string, started *time.Time) (record hotbody.Record, err error) {
 	var d map[string]interface{}
 	if err = json.Unmarshal([]byte(l), &d); err != nil {
 		return
@@ -81,10 +113,9 @@ func loadLine(l string) (record hotbody.Record, err error) {
 	recordType := d["type"].(string)
 	switch recordType {
 	case "started":
-		started, _ = common.ParseISO8601(d["time"].(string))
+		*started, _ = common.ParseISO8601(d["time"].(string))
 		return
 	case "ended":
-		ended, _ = common.ParseISO8601(d["time"].(string))
 		return
 	case "config":
 		var b []byte
@@ -126,38 +157,40 @@ func loadLine(l string) (record hotbody.Record, err error) {
 	return
 }
 
-func runResult() {
-	defer resultOutput.Close()
-
-	var err error
-
-	sc := bufio.NewScanner(resultOutput)
+// loadResult reads a result log end-to-end, handing every record after
+// the config head line to onRecord as it is read (used by --metrics-listen to drive a live
+// replay), and returns the parsed config, payment records, sebak error
+// counts, the log's own "started" timestamp and the last record's
+// timestamp. started is local to this call, so parsing several result
+// logs in one process (as `compare` does) can never leak one file's
+// start time into another's.
+func loadResult(f *os.File, onRecord func(hotbody.Record)) (config hotbody.HotterConfig, records []hotbody.Record, sebakErrors map[int]int, started, lastTime time.Time, err error) {
+	sc := bufio.NewScanner(f)
 	sc.Split(bufio.ScanLines)
 
-	var config hotbody.HotterConfig
-
 	sc.Scan()
 	headLine := sc.Text()
 
 	var record hotbody.Record
-	if record, err = loadLine(headLine); err != nil {
-		printError(resultCmd, fmt.Errorf("something wrong to read <result log>; %v; %v", err, headLine))
-	} else {
-		config = record.(hotbody.HotterConfig)
+	if record, err = loadLine(headLine, &started); err != nil {
+		return
 	}
-	log.Debug("config loaded", "config", config)
+	config = record.(hotbody.HotterConfig)
 
-	log.Debug("trying to load record")
-	var records []hotbody.Record
-	sebakErrors := map[int]int{}
+	sebakErrors = map[int]int{}
 	for sc.Scan() {
 		s := sc.Text()
 
-		if record, err = loadLine(s); err != nil {
-			printError(resultCmd, fmt.Errorf("something wrong to read <result log>; %v; %v", err, s))
+		if record, err = loadLine(s, &started); err != nil {
+			return
 		} else if record == nil {
 			continue
 		}
+
+		if onRecord != nil {
+			onRecord(record)
+		}
+
 		if record.GetType() != "payment" {
 			if sr, ok := record.(hotbody.RecordSEBAKError); ok {
 				e := sr.GetRawError()
@@ -180,53 +213,216 @@ func runResult() {
 
 		records = append(records, record)
 	}
-	log.Debug("records loaded", "count", len(records))
 
-	if len(records) < 1 {
-		fmt.Println("no records found")
-		os.Exit(1)
+	if len(records) > 0 {
+		lastTime = records[len(records)-1].GetTime()
 	}
 
 	if err = sc.Err(); err != nil {
-		printError(resultCmd, fmt.Errorf("something wrong to read <result log>; %v", err))
+		return
+	}
+
+	if started.IsZero() {
+		err = fmt.Errorf("result log has no \"started\" record")
 	}
 
-	var maxElapsedTime float64
-	var minElapsedTime float64 = -1
-	var step float64 = 50000000000
+	return
+}
+
+type kindAccumulator struct {
+	latency *hotbody.LatencyBuilder
+	count   int
+	errors  int
+}
+
+// buildResultReport runs loadResult over f and reduces the records into a
+// hotbody.ResultReport, streaming elapsed times through a LatencyBuilder
+// so the percentiles and histogram are built in a single pass with
+// bounded memory. loadResult itself still keeps every payment record in
+// memory.
+func buildResultReport(f *os.File, histogramMax time.Duration, onRecord func(hotbody.Record)) (report hotbody.ResultReport, err error) {
+	latency := hotbody.NewLatencyBuilder(histogramMax, nil)
+	byKind := map[string]*kindAccumulator{}
 
-	els := map[float64]int{}
 	var countError int
 	errorTypes := map[hotbody.RecordErrorType]int{}
-	for _, r := range records {
-		es := float64(r.GetElapsed())
 
-		i := int(es/step) * int(step)
-		els[float64(i)]++
+	config, records, sebakErrors, started, lastTime, err := loadResult(f, func(r hotbody.Record) {
+		if onRecord != nil {
+			onRecord(r)
+		}
 
-		maxElapsedTime = math.Max(maxElapsedTime, es)
-		if minElapsedTime < 0 {
-			minElapsedTime = es
-		} else {
-			minElapsedTime = math.Min(minElapsedTime, es)
+		if r.GetType() != "payment" {
+			return
 		}
 
+		elapsed := time.Duration(r.GetElapsed())
+		latency.Observe(elapsed)
+
+		kind := byKind[r.GetKind()]
+		if kind == nil {
+			kind = &kindAccumulator{latency: hotbody.NewLatencyBuilder(histogramMax, nil)}
+			byKind[r.GetKind()] = kind
+		}
+		kind.count++
+		kind.latency.Observe(elapsed)
+
 		if r.GetError() == nil {
-			continue
+			return
 		}
 		countError++
 		errorTypes[r.GetErrorType()]++
+		kind.errors++
+	})
+	if err != nil {
+		return
+	}
+
+	if len(records) < 1 {
+		err = fmt.Errorf("no records found")
+		return
+	}
+
+	totalSeconds := lastTime.Sub(started).Seconds()
+
+	kindReports := make(map[string]hotbody.KindReport, len(byKind))
+	for kind, acc := range byKind {
+		kindReports[kind] = hotbody.KindReport{
+			Requests:  acc.count,
+			Errors:    acc.errors,
+			ErrorRate: float64(acc.errors) / float64(acc.count),
+			RealOPS:   float64(acc.count-acc.errors) / totalSeconds,
+			Latency:   acc.latency.Summary(),
+		}
+	}
+
+	report = hotbody.ResultReport{
+		Config:       config,
+		Started:      started,
+		Ended:        lastTime,
+		Requests:     len(records),
+		Operations:   len(records) * config.Operations,
+		Errors:       countError,
+		ErrorRate:    float64(countError) / float64(len(records)),
+		ExpectedOPS:  float64(len(records)*config.Operations) / totalSeconds,
+		RealOPS:      float64((len(records)-countError)*config.Operations) / totalSeconds,
+		Latency:      latency.Summary(),
+		ErrorsByType: errorTypes,
+		SEBAKErrors:  sebakErrors,
+		ByKind:       kindReports,
+	}
+
+	return
+}
+
+// runReplay re-issues a scenario captured by `hotbody.ScenarioWriter`
+// against a fresh network, at the same relative timing it was recorded
+// with, after resetting every account the scenario touches.
+func runReplay() {
+	f, err := os.Open(flagReplay)
+	if err != nil {
+		printError(resultCmd, fmt.Errorf("failed to open <scenario>; %v", err))
 	}
+	defer f.Close()
 
-	var elsKeys sort.IntSlice
-	for i := float64(0); i < ((maxElapsedTime/step)*step)+step; i += step {
-		if _, ok := els[i]; !ok {
-			els[i] = 0
+	ops, err := hotbody.NewScenarioReader(f).ReadAll()
+	if err != nil {
+		printError(resultCmd, fmt.Errorf("failed to read <scenario>; %v", err))
+	}
+
+	accounts := map[string]bool{}
+	for _, op := range ops {
+		accounts[op.Source] = true
+		accounts[op.Target] = true
+	}
+	accountList := make([]string, 0, len(accounts))
+	for a := range accounts {
+		accountList = append(accountList, a)
+	}
+
+	if err := resetAccounts(accountList); err != nil {
+		printError(resultCmd, fmt.Errorf("failed to reset accounts before replay; %v", err))
+	}
+
+	log.Info("replaying scenario", "ops", len(ops))
+	if err := hotbody.Replay(ops, replaySender(hotbody.HotterConfig{})); err != nil {
+		printError(resultCmd, fmt.Errorf("replay failed; %v", err))
+	}
+
+	os.Exit(0)
+}
+
+func runResult() {
+	if len(flagReplay) > 0 {
+		runReplay()
+		return
+	}
+
+	defer resultOutput.Close()
+
+	var reporter *hotbody.MetricsReporter
+	var lastElapsed uint64
+	if len(flagMetricsListen) > 0 {
+		reporter = hotbody.NewMetricsReporterWithHistogramMax(flagHistogramMax)
+
+		go func() {
+			if err := reporter.ListenAndServeMetrics(flagMetricsListen); err != nil {
+				log.Error("metrics listener stopped", "error", err)
+			}
+		}()
+
+		stop := make(chan struct{})
+		defer close(stop)
+		reporter.StartSummaryTicker(time.Second, func(s string) { log.Info(s) }, stop)
+	}
+
+	report, err := buildResultReport(resultOutput, flagHistogramMax, func(record hotbody.Record) {
+		if reporter == nil || record.GetType() != "payment" {
+			return
 		}
-		elsKeys = append(elsKeys, int(i))
+
+		if lastElapsed > 0 && record.GetElapsed() > lastElapsed {
+			time.Sleep(time.Duration(record.GetElapsed() - lastElapsed))
+		}
+		lastElapsed = record.GetElapsed()
+
+		reporter.IncInFlight()
+		if record.GetError() == nil {
+			reporter.RecordPayment(time.Duration(record.GetElapsed()))
+		} else {
+			reporter.RecordError(record.GetErrorType())
+		}
+	})
+	if err != nil {
+		printError(resultCmd, fmt.Errorf("something wrong to read <result log>; %v", err))
+	}
+
+	if flagOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			printError(resultCmd, fmt.Errorf("failed to encode result as json; %v", err))
+		}
+
+		os.Exit(0)
 	}
 
-	sort.Sort(elsKeys)
+	renderResultTable(report)
+
+	os.Exit(0)
+}
+
+// renderResultTable prints a ResultReport as the human-readable ASCII
+// table shown by `result`.
+func renderResultTable(report hotbody.ResultReport) {
+	config := report.Config
+	lastTime := report.Ended
+	countError := report.Errors
+	errorTypes := report.ErrorsByType
+	sebakErrors := report.SEBAKErrors
+	latencySummary := report.Latency
+	expectedOPS := report.ExpectedOPS
+	realOPS := report.RealOPS
 
 	alignKey := func(s string) string {
 		return fmt.Sprintf("% 20s", s)
@@ -275,58 +471,81 @@ func runResult() {
 		Row = append(Row, []string{alignHead("node"), alignKey("block totalops"), alignValue(config.Node.Block.TotalOps)})
 	}
 
-	lastTime := records[len(records)-1].GetTime()
-
 	if !flagBrief {
-		Row = append(Row, []string{alignHead("time"), alignKey("started"), alignValue(FormatISO8601(started))})
+		Row = append(Row, []string{alignHead("time"), alignKey("started"), alignValue(FormatISO8601(report.Started))})
 		Row = append(Row, []string{alignHead("time"), alignKey("ended"), alignValue(FormatISO8601(lastTime))})
-		Row = append(Row, []string{alignHead("time"), alignKey("total elapsed"), alignValue(lastTime.Sub(started))})
+		Row = append(Row, []string{alignHead("time"), alignKey("total elapsed"), alignValue(lastTime.Sub(report.Started))})
 	}
 
 	{
-
-		Row = append(Row, []string{alignHead("result"), alignKey("# requests"), alignValue(len(records))})
-		Row = append(Row, []string{alignHead("result"), alignKey("# operations"), alignValue(len(records) * config.Operations)})
+		Row = append(Row, []string{alignHead("result"), alignKey("# requests"), alignValue(report.Requests)})
+		Row = append(Row, []string{alignHead("result"), alignKey("# operations"), alignValue(report.Operations)})
 		Row = append(Row, []string{
 			alignHead("result"),
 			alignKey("error rates"),
 			alignValue(
 				fmt.Sprintf(
 					"%2.5f％ (%d/%d)",
-					float64(countError)/float64(len(records))*100,
+					report.ErrorRate*100,
 					countError,
-					len(records),
+					report.Requests,
 				),
 			),
 		})
-		Row = append(Row, []string{alignHead("result"), alignKey("max elapsed time"), alignValue(maxElapsedTime / float64(10000000000))})
-		Row = append(Row, []string{alignHead("result"), alignKey("min elapsed time"), alignValue(minElapsedTime / float64(10000000000))})
-		Row = append(Row, []string{alignHead("result"), alignKey("distribution"), ""})
-		for _, e := range elsKeys {
-			span := int(float64(e) / float64(10000000000))
-			c := els[float64(e)]
+		Row = append(Row, []string{alignHead("result"), alignKey("min elapsed time"), alignValue(latencySummary.Min)})
+		Row = append(Row, []string{alignHead("result"), alignKey("max elapsed time"), alignValue(latencySummary.Max)})
+		Row = append(Row, []string{alignHead("result"), alignKey("p50"), alignValue(latencySummary.P50)})
+		Row = append(Row, []string{alignHead("result"), alignKey("p90"), alignValue(latencySummary.P90)})
+		Row = append(Row, []string{alignHead("result"), alignKey("p95"), alignValue(latencySummary.P95)})
+		Row = append(Row, []string{alignHead("result"), alignKey("p99"), alignValue(latencySummary.P99)})
+		Row = append(Row, []string{alignHead("result"), alignKey("p99.9"), alignValue(latencySummary.P999)})
+		Row = append(Row, []string{alignHead("result"), alignKey("histogram"), ""})
+		for _, bucket := range latencySummary.Histogram {
+			upperBound := bucket.UpperBound.String()
+			if bucket.UpperBound == hotbody.OverflowBound {
+				upperBound = "+Inf"
+			}
 
 			Row = append(Row, []string{
 				alignHead("result"),
-				"", 
+				"",
 				alignValue(
 					fmt.Sprintf(
-						"%2d-%-2d: %8.5f％ / %5d",
-						span,
-						span+int(step/float64(10000000000)),
-						float64(c)/float64(len(records))*100,
-						c,
+						"<=%-10s: %8.5f％ / %5d",
+						upperBound,
+						float64(bucket.Count)/float64(report.Requests)*100,
+						bucket.Count,
 					),
 				),
 			})
 		}
 
-		totalSeconds := lastTime.Sub(started).Seconds()
+		Row = append(Row, []string{alignHead("result"), alignKey("expected OPS"), string(int(expectedOPS))})
+		Row = append(Row, []string{alignHead("result"), alignKey("real OPS"), string(int(realOPS))})
+	}
 
-		ops := float64((len(records))*config.Operations) / float64(totalSeconds)
-		Row = append(Row, []string{alignHead("result"), alignKey("expected OPS"), string(int(ops))})
-		ops = float64((len(records)-countError)*config.Operations) / float64(totalSeconds)
-		Row = append(Row, []string{alignHead("result"), alignKey("real OPS"), string(int(ops))})
+	if len(report.ByKind) > 1 {
+		var c int
+		for kind, kr := range report.ByKind {
+			h := ""
+			if c == 0 {
+				h = alignHead("by-kind")
+			}
+			c++
+			Row = append(Row, []string{
+				h,
+				alignKey(kind),
+				alignValue(
+					fmt.Sprintf(
+						"%5d reqs | %8.5f％ err | p99 %s | %.2f OPS",
+						kr.Requests,
+						kr.ErrorRate*100,
+						kr.Latency.P99,
+						kr.RealOPS,
+					),
+				),
+			})
+		}
 	}
 
 	{
@@ -391,10 +610,9 @@ func runResult() {
 			}
 		}
 	}
+
 	table.SetAutoMergeCells(true)
 	table.SetRowLine(true)
 	table.AppendBulk(Row)
 	table.Render()
-
-	os.Exit(0)
 }
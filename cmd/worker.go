@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/spikeekips/sebak-hot-body/hotbody"
+)
+
+var (
+	workerCmd             *cobra.Command
+	flagCoordinatorAddr   string
+	flagWorkerID          string
+	flagWorkerBatchWindow time.Duration = time.Second
+)
+
+func init() {
+	workerCmd = &cobra.Command{
+		Use:   "worker <coordinator addr>",
+		Short: "Connect to a coordinator and drive this worker's assigned account slice",
+		Run: func(c *cobra.Command, args []string) {
+			parseWorkerFlags(args)
+
+			runWorker()
+		},
+	}
+
+	workerCmd.Flags().StringVar(&flagLogLevel, "log-level", flagLogLevel, "log level, {crit, error, warn, info, debug}")
+	workerCmd.Flags().StringVar(&flagLogFormat, "log-format", flagLogFormat, "log format, {terminal, json}")
+	workerCmd.Flags().StringVar(&flagLog, "log", flagLog, "set log file")
+	workerCmd.Flags().StringVar(&flagWorkerID, "id", flagWorkerID, "unique id this worker registers with")
+	workerCmd.Flags().DurationVar(&flagWorkerBatchWindow, "batch-window", flagWorkerBatchWindow, "how often to stream accumulated records back to the coordinator")
+
+	rootCmd.AddCommand(workerCmd)
+}
+
+func parseWorkerFlags(args []string) {
+	setLogging()
+
+	if len(args) < 1 {
+		printError(workerCmd, fmt.Errorf("<coordinator addr> is missing"))
+	}
+	flagCoordinatorAddr = args[0]
+
+	if len(flagWorkerID) < 1 {
+		printError(workerCmd, fmt.Errorf("--id is required"))
+	}
+}
+
+// generatePayment is how a worker actually issues one payment against its
+// assigned account slice. It defaults to a dry-run generator that only
+// logs, since this package does not carry the hotter's own sebak client;
+// a build wiring that client in replaces this with one that sends for
+// real, the same way replaySender/resetAccounts are overridden in
+// cmd/result.go.
+var generatePayment = func(assignment hotbody.WorkerAssignment) hotbody.Record {
+	source := fmt.Sprintf("account-%d", assignment.Accounts.Start)
+	target := fmt.Sprintf("account-%d", assignment.Accounts.Start+1)
+
+	log.Debug("generating payment (dry-run)", "source", source, "target", target)
+
+	return hotbody.RecordPayment{
+		Type:   "payment",
+		Time:   time.Now(),
+		Source: source,
+		Target: target,
+		Amount: 1,
+		Kind:   "payment",
+	}
+}
+
+func runWorker() {
+	client := &hotbody.WorkerClient{CoordinatorAddr: flagCoordinatorAddr, ID: flagWorkerID}
+
+	assignment, err := client.Register()
+	if err != nil {
+		printError(workerCmd, fmt.Errorf("failed to register with coordinator; %v", err))
+	}
+	log.Info("registered with coordinator", "accounts", assignment.Accounts, "target-ops", assignment.TargetOPS)
+
+	sendInterval := time.Second
+	if assignment.TargetOPS > 0 {
+		sendInterval = time.Second / time.Duration(assignment.TargetOPS)
+	}
+
+	batch := make([]hotbody.Record, 0, 128)
+	var mu sync.Mutex
+
+	sendTicker := time.NewTicker(sendInterval)
+	defer sendTicker.Stop()
+	go func() {
+		for range sendTicker.C {
+			record := generatePayment(assignment)
+
+			mu.Lock()
+			batch = append(batch, record)
+			mu.Unlock()
+		}
+	}()
+
+	flushTicker := time.NewTicker(flagWorkerBatchWindow)
+	defer flushTicker.Stop()
+
+	for range flushTicker.C {
+		mu.Lock()
+		pending := batch
+		batch = make([]hotbody.Record, 0, 128)
+		mu.Unlock()
+
+		if len(pending) < 1 {
+			continue
+		}
+
+		if err := client.SendRecords(pending); err != nil {
+			log.Error("failed to stream records to coordinator", "error", err)
+			continue
+		}
+	}
+}
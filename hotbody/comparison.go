@@ -0,0 +1,58 @@
+package hotbody
+
+// ResultDelta captures how one result report moved relative to a
+// baseline: a positive OPS delta is an improvement, positive p99 latency
+// and error-rate deltas are regressions.
+type ResultDelta struct {
+	Baseline         ResultReport `json:"baseline"`
+	Candidate        ResultReport `json:"candidate"`
+	OPSChange        float64      `json:"ops_change"`        // (candidate-baseline)/baseline, e.g. -0.05 = 5% slower
+	ErrorRateChange  float64      `json:"error_rate_change"` // candidate.ErrorRate - baseline.ErrorRate
+	P99Change        float64      `json:"p99_change"`        // (candidate-baseline)/baseline, e.g. 0.2 = 20% slower
+	SEBAKErrorsDelta map[int]int  `json:"sebak_errors_delta"`
+}
+
+// CompareResults reduces a baseline/candidate pair of ResultReports into
+// a ResultDelta.
+func CompareResults(baseline, candidate ResultReport) ResultDelta {
+	delta := ResultDelta{
+		Baseline:         baseline,
+		Candidate:        candidate,
+		ErrorRateChange:  candidate.ErrorRate - baseline.ErrorRate,
+		SEBAKErrorsDelta: map[int]int{},
+	}
+
+	if baseline.RealOPS != 0 {
+		delta.OPSChange = (candidate.RealOPS - baseline.RealOPS) / baseline.RealOPS
+	}
+	if baseline.Latency.P99 != 0 {
+		delta.P99Change = float64(candidate.Latency.P99-baseline.Latency.P99) / float64(baseline.Latency.P99)
+	}
+
+	codes := map[int]bool{}
+	for code := range baseline.SEBAKErrors {
+		codes[code] = true
+	}
+	for code := range candidate.SEBAKErrors {
+		codes[code] = true
+	}
+	for code := range codes {
+		delta.SEBAKErrorsDelta[code] = candidate.SEBAKErrors[code] - baseline.SEBAKErrors[code]
+	}
+
+	return delta
+}
+
+// Regressed reports whether this delta exceeds either threshold: an OPS
+// drop of at least opsThreshold (e.g. 0.05 for 5%) or a p99 increase of
+// at least p99Threshold (e.g. 0.2 for 20%). A zero threshold disables
+// that check.
+func (d ResultDelta) Regressed(opsThreshold, p99Threshold float64) bool {
+	if opsThreshold > 0 && -d.OPSChange >= opsThreshold {
+		return true
+	}
+	if p99Threshold > 0 && d.P99Change >= p99Threshold {
+		return true
+	}
+	return false
+}
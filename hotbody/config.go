@@ -0,0 +1,59 @@
+package hotbody
+
+import "time"
+
+// HotterConfigPolicy mirrors the subset of a sebak node's consensus
+// policy that is useful context for a result report.
+type HotterConfigPolicy struct {
+	NetworkID      string `json:"network_id"`
+	InitialBalance string `json:"initial_balance"`
+	BlockTime      string `json:"block_time"`
+	BaseReserve    string `json:"base_reserve"`
+	BaseFee        string `json:"base_fee"`
+}
+
+// HotterConfigNodeInfo mirrors the subset of a sebak node's own identity
+// that is useful context for a result report.
+type HotterConfigNodeInfo struct {
+	Endpoint string `json:"endpoint"`
+	Address  string `json:"address"`
+	State    string `json:"state"`
+}
+
+// HotterConfigBlock mirrors the node's block height at the time the run
+// started.
+type HotterConfigBlock struct {
+	Height    uint64 `json:"height"`
+	Hash      string `json:"hash"`
+	TotalTxs  uint64 `json:"total_txs"`
+	TotalOps  uint64 `json:"total_ops"`
+}
+
+// HotterConfigNode groups everything the hotter learned about the target
+// node before starting the run.
+type HotterConfigNode struct {
+	Policy HotterConfigPolicy   `json:"policy"`
+	Node   HotterConfigNodeInfo `json:"node"`
+	Block  HotterConfigBlock    `json:"block"`
+}
+
+// HotterConfig is the first record of every result log: the full set of
+// flags and discovered network state the hotter ran with.
+type HotterConfig struct {
+	Type            string            `json:"type"`
+	Time            time.Time         `json:"time"`
+	Timeout         time.Duration     `json:"timeout"`
+	T               int               `json:"t"`
+	InitAccount     string            `json:"init_account"`
+	RequestTimeout  time.Duration     `json:"request_timeout"`
+	ConfirmDuration time.Duration     `json:"confirm_duration"`
+	Operations      int               `json:"operations"`
+	Node            HotterConfigNode  `json:"node"`
+}
+
+func (c HotterConfig) GetType() string             { return c.Type }
+func (c HotterConfig) GetTime() time.Time          { return c.Time }
+func (c HotterConfig) GetElapsed() uint64          { return 0 }
+func (c HotterConfig) GetError() error             { return nil }
+func (c HotterConfig) GetErrorType() RecordErrorType { return RecordErrorTypeNone }
+func (c HotterConfig) GetKind() string               { return "config" }
@@ -0,0 +1,128 @@
+package hotbody
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// AccountRange is a contiguous slice, by index into the run's account
+// list, handed to exactly one worker so no two workers ever spend from
+// the same source account concurrently.
+type AccountRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"` // exclusive
+}
+
+// WorkerRegistration is what a worker sends the coordinator on startup.
+type WorkerRegistration struct {
+	ID string `json:"id"`
+}
+
+// WorkerAssignment is what the coordinator hands back to a registered
+// worker: the account range it owns and the OPS it should aim for.
+type WorkerAssignment struct {
+	Accounts  AccountRange `json:"accounts"`
+	TargetOPS int          `json:"target_ops"`
+}
+
+// Coordinator splits a fixed account list and target OPS across a known
+// number of workers, gates the run's start on all of them registering,
+// and merges every worker's streamed Records into one unified result
+// log that `result` can consume unchanged.
+type Coordinator struct {
+	sync.Mutex
+
+	numWorkers  int
+	assignments []WorkerAssignment
+	registered  map[string]int
+	startCh     chan struct{}
+	started     bool
+	writer      io.Writer
+}
+
+// NewCoordinator builds a Coordinator for numWorkers workers, splitting
+// accounts and totalTargetOPS evenly between them. Every Record ingested
+// via Ingest is appended to writer as its own JSON line. numWorkers must
+// be at least 1.
+func NewCoordinator(accounts []string, numWorkers, totalTargetOPS int, writer io.Writer) (*Coordinator, error) {
+	if numWorkers < 1 {
+		return nil, fmt.Errorf("numWorkers must be at least 1, got %d", numWorkers)
+	}
+
+	c := &Coordinator{
+		numWorkers: numWorkers,
+		registered: map[string]int{},
+		startCh:    make(chan struct{}),
+		writer:     writer,
+	}
+	c.assignments = splitAssignments(accounts, numWorkers, totalTargetOPS)
+
+	return c, nil
+}
+
+func splitAssignments(accounts []string, numWorkers, totalTargetOPS int) []WorkerAssignment {
+	perWorker := len(accounts) / numWorkers
+	opsPerWorker := totalTargetOPS / numWorkers
+
+	out := make([]WorkerAssignment, numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		start := i * perWorker
+		end := start + perWorker
+		if i == numWorkers-1 {
+			end = len(accounts)
+		}
+
+		out[i] = WorkerAssignment{
+			Accounts:  AccountRange{Start: start, End: end},
+			TargetOPS: opsPerWorker,
+		}
+	}
+
+	return out
+}
+
+// Register assigns reg a free account range, or returns its existing
+// assignment if it already registered. Once every expected worker has
+// registered, WaitForStart unblocks for all of them.
+func (c *Coordinator) Register(reg WorkerRegistration) (WorkerAssignment, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if idx, ok := c.registered[reg.ID]; ok {
+		return c.assignments[idx], nil
+	}
+
+	idx := len(c.registered)
+	if idx >= c.numWorkers {
+		return WorkerAssignment{}, fmt.Errorf("no free worker slots left, expected %d", c.numWorkers)
+	}
+	c.registered[reg.ID] = idx
+
+	if len(c.registered) == c.numWorkers && !c.started {
+		c.started = true
+		close(c.startCh)
+	}
+
+	return c.assignments[idx], nil
+}
+
+// WaitForStart blocks until every expected worker has registered.
+func (c *Coordinator) WaitForStart() { <-c.startCh }
+
+// Ingest appends a batch of already-serialized Record lines a worker
+// streamed back to the unified result log, one JSON line per record, in
+// the same format a single-box hotter run already produces.
+func (c *Coordinator) Ingest(records []json.RawMessage) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, r := range records {
+		if _, err := c.writer.Write(append(r, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
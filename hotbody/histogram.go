@@ -0,0 +1,79 @@
+package hotbody
+
+import (
+	"math"
+	"time"
+)
+
+// HistogramBucket is a single non-cumulative bucket: Count is the number
+// of samples greater than the previous bucket's UpperBound and less than
+// or equal to this one's. Summing the counts up to a bucket gives the
+// cumulative Prometheus/OpenMetrics value.
+type HistogramBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      int64         `json:"count"`
+}
+
+// OverflowBound is the UpperBound a Histogram reports for samples that
+// exceed every finite bucket, the same role +Inf plays in a Prometheus
+// histogram.
+const OverflowBound = time.Duration(math.MaxInt64)
+
+// DefaultHistogramBounds returns the log-linear bucket boundaries used
+// when a caller does not supply its own: 1ms, 2ms, 5ms, 10ms, 20ms, 50ms
+// ... in 1-2-5 steps per decade, ending with the first bound greater than
+// max (e.g. ..., 100ms, 200ms for a max of 100ms).
+func DefaultHistogramBounds(max time.Duration) []time.Duration {
+	bounds := []time.Duration{}
+	steps := []int64{1, 2, 5}
+
+	for mag := time.Millisecond; mag < max*10; mag *= 10 {
+		for _, step := range steps {
+			b := time.Duration(step) * mag
+			if b > max {
+				return append(bounds, b)
+			}
+			bounds = append(bounds, b)
+		}
+	}
+
+	return bounds
+}
+
+// Histogram accumulates samples into a fixed, caller-supplied set of
+// buckets in a single pass.
+type Histogram struct {
+	bounds   []time.Duration
+	counts   []int64
+	overflow int64
+}
+
+// NewHistogram builds a Histogram over the given upper bounds, which must
+// be sorted ascending.
+func NewHistogram(bounds []time.Duration) *Histogram {
+	return &Histogram{bounds: bounds, counts: make([]int64, len(bounds))}
+}
+
+// Observe records a single elapsed duration. Samples greater than every
+// bound are counted separately rather than folded into the last finite
+// bucket; see Buckets.
+func (h *Histogram) Observe(d time.Duration) {
+	for i, b := range h.bounds {
+		if d <= b {
+			h.counts[i]++
+			return
+		}
+	}
+	h.overflow++
+}
+
+// Buckets returns the accumulated bucket counts, plus a trailing
+// OverflowBound bucket for samples that exceeded every finite bound.
+func (h *Histogram) Buckets() []HistogramBucket {
+	out := make([]HistogramBucket, len(h.bounds)+1)
+	for i, b := range h.bounds {
+		out[i] = HistogramBucket{UpperBound: b, Count: h.counts[i]}
+	}
+	out[len(h.bounds)] = HistogramBucket{UpperBound: OverflowBound, Count: h.overflow}
+	return out
+}
@@ -0,0 +1,55 @@
+package hotbody
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramObserve(t *testing.T) {
+	bounds := []time.Duration{time.Millisecond, 5 * time.Millisecond, 10 * time.Millisecond}
+	h := NewHistogram(bounds)
+
+	samples := []time.Duration{
+		time.Millisecond / 2, // <= 1ms
+		time.Millisecond,     // <= 1ms
+		3 * time.Millisecond, // <= 5ms
+		9 * time.Millisecond, // <= 10ms
+		time.Second,          // overflow
+		2 * time.Second,      // overflow
+	}
+	for _, s := range samples {
+		h.Observe(s)
+	}
+
+	buckets := h.Buckets()
+	if len(buckets) != len(bounds)+1 {
+		t.Fatalf("len(Buckets()) = %d, want %d", len(buckets), len(bounds)+1)
+	}
+
+	want := map[time.Duration]int64{
+		time.Millisecond:      2,
+		5 * time.Millisecond:  1,
+		10 * time.Millisecond: 1,
+		OverflowBound:         2,
+	}
+	for _, b := range buckets {
+		if b.Count != want[b.UpperBound] {
+			t.Errorf("bucket <=%v count = %d, want %d", b.UpperBound, b.Count, want[b.UpperBound])
+		}
+	}
+}
+
+func TestDefaultHistogramBounds(t *testing.T) {
+	bounds := DefaultHistogramBounds(100 * time.Millisecond)
+	if len(bounds) < 1 {
+		t.Fatal("DefaultHistogramBounds returned no bounds")
+	}
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			t.Fatalf("bounds not strictly ascending at index %d: %v <= %v", i, bounds[i], bounds[i-1])
+		}
+	}
+	if bounds[len(bounds)-1] < 100*time.Millisecond {
+		t.Errorf("last bound %v is below max 100ms", bounds[len(bounds)-1])
+	}
+}
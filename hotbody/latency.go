@@ -0,0 +1,82 @@
+package hotbody
+
+import "time"
+
+// DefaultQuantileEpsilon is the rank-error bound used for the
+// percentiles reported by LatencySummary unless a caller overrides it.
+const DefaultQuantileEpsilon = 0.001
+
+// LatencySummary is the latency half of a result report: streaming
+// percentile estimates plus a histogram, both built in a single pass
+// over a (possibly very large) result log.
+type LatencySummary struct {
+	Count      int64             `json:"count"`
+	P50        time.Duration     `json:"p50"`
+	P90        time.Duration     `json:"p90"`
+	P95        time.Duration     `json:"p95"`
+	P99        time.Duration     `json:"p99"`
+	P999       time.Duration     `json:"p999"`
+	Min        time.Duration     `json:"min"`
+	Max        time.Duration     `json:"max"`
+	Histogram  []HistogramBucket `json:"histogram"`
+}
+
+// LatencyBuilder accumulates elapsed durations and produces a
+// LatencySummary, streaming the percentile estimation through a
+// QuantileSketch so result logs of any size can be processed in bounded
+// memory.
+type LatencyBuilder struct {
+	sketch    *QuantileSketch
+	histogram *Histogram
+	min       time.Duration
+	max       time.Duration
+	count     int64
+}
+
+// NewLatencyBuilder returns a LatencyBuilder that will bucket samples
+// into bounds, or DefaultHistogramBounds(max) if bounds is nil.
+func NewLatencyBuilder(max time.Duration, bounds []time.Duration) *LatencyBuilder {
+	if bounds == nil {
+		bounds = DefaultHistogramBounds(max)
+	}
+
+	return &LatencyBuilder{
+		sketch:    NewQuantileSketch(DefaultQuantileEpsilon),
+		histogram: NewHistogram(bounds),
+		min:       -1,
+	}
+}
+
+// Observe records a single elapsed duration.
+func (b *LatencyBuilder) Observe(d time.Duration) {
+	b.count++
+	b.sketch.Insert(float64(d))
+	b.histogram.Observe(d)
+
+	if b.min < 0 || d < b.min {
+		b.min = d
+	}
+	if d > b.max {
+		b.max = d
+	}
+}
+
+// Summary finalizes the accumulated samples into a LatencySummary.
+func (b *LatencyBuilder) Summary() LatencySummary {
+	min := b.min
+	if min < 0 {
+		min = 0
+	}
+
+	return LatencySummary{
+		Count:     b.count,
+		P50:       time.Duration(b.sketch.Quantile(0.50)),
+		P90:       time.Duration(b.sketch.Quantile(0.90)),
+		P95:       time.Duration(b.sketch.Quantile(0.95)),
+		P99:       time.Duration(b.sketch.Quantile(0.99)),
+		P999:      time.Duration(b.sketch.Quantile(0.999)),
+		Min:       min,
+		Max:       b.max,
+		Histogram: b.histogram.Buckets(),
+	}
+}
@@ -0,0 +1,109 @@
+package hotbody
+
+import "sort"
+
+// quantileTuple is a single summary entry of the Greenwald-Khanna
+// quantile sketch: v is the sampled value, g is the number of
+// values represented by this tuple since the previous one, and delta is
+// the maximum rank error this tuple may introduce.
+type quantileTuple struct {
+	v     float64
+	g     int64
+	delta int64
+}
+
+// QuantileSketch estimates arbitrary quantiles of a stream of float64
+// samples in a single pass and bounded memory, using the
+// Greenwald-Khanna algorithm (the same family as the Cormode-Korn-
+// Muthukrishnan biased sketch referenced in the design doc). It is not
+// safe for concurrent use; callers insert from a single goroutine and
+// read back once the stream is exhausted.
+type QuantileSketch struct {
+	epsilon float64
+	n       int64
+	tuples  []quantileTuple
+}
+
+// NewQuantileSketch returns a sketch guaranteeing every Quantile() call
+// is accurate to within epsilon of the true rank (e.g. 0.01 for 1%).
+func NewQuantileSketch(epsilon float64) *QuantileSketch {
+	return &QuantileSketch{epsilon: epsilon}
+}
+
+// Insert adds a single sample to the sketch.
+func (s *QuantileSketch) Insert(v float64) {
+	s.n++
+
+	i := sort.Search(len(s.tuples), func(i int) bool { return s.tuples[i].v >= v })
+
+	var delta int64
+	if i == 0 || i == len(s.tuples) {
+		delta = 0
+	} else {
+		delta = int64(2*s.epsilon*float64(s.n)) - 1
+		if delta < 0 {
+			delta = 0
+		}
+	}
+
+	tuple := quantileTuple{v: v, g: 1, delta: delta}
+
+	s.tuples = append(s.tuples, quantileTuple{})
+	copy(s.tuples[i+1:], s.tuples[i:])
+	s.tuples[i] = tuple
+
+	if s.n%int64(1/(2*s.epsilon)+1) == 0 {
+		s.compress()
+	}
+}
+
+// compress removes tuples that can be folded into their right neighbour
+// without the neighbour's own [g,delta] band exceeding the error bound,
+// keeping the sketch's memory bounded regardless of stream length. A
+// removed tuple's g is added onto the neighbour that absorbs it; the
+// neighbour's v and delta - which is what actually bounds its rank
+// error - are left untouched, since it is always the tuple being kept,
+// never the one being discarded, whose delta has to keep holding.
+func (s *QuantileSketch) compress() {
+	if len(s.tuples) < 3 {
+		return
+	}
+
+	band := int64(2 * s.epsilon * float64(s.n))
+	if band < 1 {
+		band = 1
+	}
+
+	for i := len(s.tuples) - 2; i >= 1; i-- {
+		next := s.tuples[i+1]
+		if s.tuples[i].g+next.g+next.delta <= band {
+			s.tuples[i+1].g += s.tuples[i].g
+			s.tuples = append(s.tuples[:i], s.tuples[i+1:]...)
+		}
+	}
+}
+
+// Quantile returns the estimated value at rank q (0-1). It returns 0 if
+// no samples have been inserted yet.
+func (s *QuantileSketch) Quantile(q float64) float64 {
+	if len(s.tuples) < 1 {
+		return 0
+	}
+
+	target := int64(q * float64(s.n))
+	allowed := int64(s.epsilon * float64(s.n))
+
+	var rmin int64
+	for _, t := range s.tuples {
+		rmin += t.g
+		rmax := rmin + t.delta
+		if target-rmin <= allowed && rmax-target <= allowed {
+			return t.v
+		}
+	}
+
+	return s.tuples[len(s.tuples)-1].v
+}
+
+// Count returns the number of samples inserted so far.
+func (s *QuantileSketch) Count() int64 { return s.n }
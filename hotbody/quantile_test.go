@@ -0,0 +1,63 @@
+package hotbody
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestQuantileSketchUniform(t *testing.T) {
+	const n = 20000
+	epsilon := DefaultQuantileEpsilon
+
+	sketch := NewQuantileSketch(epsilon)
+
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = r.Float64() * 1000
+		sketch.Insert(values[i])
+	}
+
+	if sketch.Count() != n {
+		t.Fatalf("Count() = %d, want %d", sketch.Count(), n)
+	}
+
+	sortedValues := append([]float64(nil), values...)
+	sort.Float64s(sortedValues)
+
+	// The sketch's documented guarantee is rank-accurate to within
+	// epsilon*n of the true rank, per the Greenwald-Khanna bound.
+	allowed := epsilon * float64(n)
+
+	for _, q := range []float64{0.5, 0.9, 0.95, 0.99, 0.999} {
+		got := sketch.Quantile(q)
+		want := sortedValues[int(q*float64(n))]
+
+		wantRank := int(q * float64(n))
+		gotRank := rankOf(sortedValues, got)
+		if math.Abs(float64(gotRank-wantRank)) > allowed {
+			t.Errorf("Quantile(%v) = %v (rank %d), want near %v (rank %d), allowed error %v ranks", q, got, gotRank, want, wantRank, allowed)
+		}
+	}
+}
+
+func TestQuantileSketchEmpty(t *testing.T) {
+	sketch := NewQuantileSketch(0.01)
+	if got := sketch.Quantile(0.5); got != 0 {
+		t.Errorf("Quantile(0.5) on empty sketch = %v, want 0", got)
+	}
+	if got := sketch.Count(); got != 0 {
+		t.Errorf("Count() on empty sketch = %d, want 0", got)
+	}
+}
+
+func rankOf(sorted []float64, v float64) int {
+	for i, s := range sorted {
+		if s >= v {
+			return i
+		}
+	}
+	return len(sorted) - 1
+}
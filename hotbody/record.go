@@ -0,0 +1,125 @@
+package hotbody
+
+import (
+	"errors"
+	"time"
+)
+
+// RecordErrorType classifies the kind of failure a Record carries, so
+// callers can aggregate counts without inspecting the underlying error.
+type RecordErrorType string
+
+const (
+	RecordErrorTypeNone    RecordErrorType = ""
+	RecordErrorTypeTimeout RecordErrorType = "timeout"
+	RecordErrorTypeSEBAK   RecordErrorType = "sebak-error"
+	RecordErrorTypeUnknown RecordErrorType = "unknown"
+)
+
+// Record is a single line of a hotbody result log: a config header, an
+// account-creation batch, a payment attempt or a raw sebak error.
+type Record interface {
+	GetType() string
+	GetTime() time.Time
+	GetElapsed() uint64
+	GetError() error
+	GetErrorType() RecordErrorType
+
+	// GetKind returns the operation kind this record belongs to, e.g.
+	// "payment" or "payment-batch-10", so a workload mixing several
+	// kinds can be broken down per kind in a result report.
+	GetKind() string
+}
+
+// RecordCreateAccounts is emitted once the initial batch of accounts used
+// to fund the run has been created.
+type RecordCreateAccounts struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Elapsed  uint64    `json:"elapsed"`
+	Accounts []string  `json:"accounts"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func (r RecordCreateAccounts) GetType() string      { return r.Type }
+func (r RecordCreateAccounts) GetTime() time.Time   { return r.Time }
+func (r RecordCreateAccounts) GetElapsed() uint64   { return r.Elapsed }
+func (r RecordCreateAccounts) GetError() error {
+	if len(r.Error) < 1 {
+		return nil
+	}
+	return errors.New(r.Error)
+}
+func (r RecordCreateAccounts) GetErrorType() RecordErrorType {
+	if r.GetError() == nil {
+		return RecordErrorTypeNone
+	}
+	return RecordErrorTypeUnknown
+}
+func (r RecordCreateAccounts) GetKind() string { return "create-account" }
+
+// RecordPayment is emitted for every payment operation the hotter sends,
+// successful or not.
+type RecordPayment struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Elapsed uint64    `json:"elapsed"`
+	Source  string    `json:"source"`
+	Target  string    `json:"target"`
+	Amount  uint64    `json:"amount"`
+	Error   string    `json:"error,omitempty"`
+
+	// ErrorType classifies Error, e.g. "timeout" or "sebak-error". Older
+	// result logs without this field fall back to RecordErrorTypeUnknown
+	// whenever Error is set, rather than guessing a specific cause.
+	ErrorType RecordErrorType `json:"error_type,omitempty"`
+
+	// Kind is the workload-mix operation this payment was generated
+	// for, e.g. "payment" or "payment-batch-10". Older result logs
+	// without this field are treated as plain "payment".
+	Kind string `json:"kind,omitempty"`
+}
+
+func (r RecordPayment) GetType() string    { return r.Type }
+func (r RecordPayment) GetTime() time.Time { return r.Time }
+func (r RecordPayment) GetElapsed() uint64 { return r.Elapsed }
+func (r RecordPayment) GetError() error {
+	if len(r.Error) < 1 {
+		return nil
+	}
+	return errors.New(r.Error)
+}
+func (r RecordPayment) GetErrorType() RecordErrorType {
+	if r.GetError() == nil {
+		return RecordErrorTypeNone
+	}
+	if len(r.ErrorType) < 1 {
+		return RecordErrorTypeUnknown
+	}
+	return r.ErrorType
+}
+func (r RecordPayment) GetKind() string {
+	if len(r.Kind) < 1 {
+		return "payment"
+	}
+	return r.Kind
+}
+
+// RecordSEBAKError wraps an error response returned by a sebak node, kept
+// as a raw map since its shape depends on the node's own error body.
+type RecordSEBAKError struct {
+	Type     string                 `json:"type"`
+	Time     time.Time              `json:"time"`
+	Elapsed  uint64                 `json:"elapsed"`
+	RawError map[string]interface{} `json:"error"`
+}
+
+func (r RecordSEBAKError) GetType() string    { return r.Type }
+func (r RecordSEBAKError) GetTime() time.Time { return r.Time }
+func (r RecordSEBAKError) GetElapsed() uint64 { return r.Elapsed }
+func (r RecordSEBAKError) GetError() error {
+	return errors.New("sebak-error")
+}
+func (r RecordSEBAKError) GetErrorType() RecordErrorType { return RecordErrorTypeSEBAK }
+func (r RecordSEBAKError) GetKind() string { return "sebak-error" }
+func (r RecordSEBAKError) GetRawError() map[string]interface{} { return r.RawError }
@@ -0,0 +1,35 @@
+package hotbody
+
+import "time"
+
+// ResultReport is the machine-readable summary of a single result log,
+// shared by the human-facing table renderer and the `--output json`
+// path so both always agree on what a "run" looked like.
+type ResultReport struct {
+	Config       HotterConfig             `json:"config"`
+	Started      time.Time                `json:"started"`
+	Ended        time.Time                `json:"ended"`
+	Requests     int                      `json:"requests"`
+	Operations   int                      `json:"operations"`
+	Errors       int                      `json:"errors"`
+	ErrorRate    float64                  `json:"error_rate"`
+	ExpectedOPS  float64                  `json:"expected_ops"`
+	RealOPS      float64                  `json:"real_ops"`
+	Latency      LatencySummary           `json:"latency"`
+	ErrorsByType map[RecordErrorType]int  `json:"errors_by_type"`
+	SEBAKErrors  map[int]int              `json:"sebak_errors"`
+
+	// ByKind breaks the same figures down per workload-mix operation
+	// kind (see Record.GetKind), so e.g. batched payments can be
+	// compared against singletons within one run.
+	ByKind map[string]KindReport `json:"by_kind,omitempty"`
+}
+
+// KindReport is ResultReport's per-operation-kind breakdown.
+type KindReport struct {
+	Requests  int            `json:"requests"`
+	Errors    int            `json:"errors"`
+	ErrorRate float64        `json:"error_rate"`
+	RealOPS   float64        `json:"real_ops"`
+	Latency   LatencySummary `json:"latency"`
+}
@@ -0,0 +1,256 @@
+package hotbody
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reporter receives every record a hotter run (or a replayed result log)
+// produces and keeps it available as live metrics. Implementations must
+// be safe for concurrent use, since IncInFlight/RecordPayment/RecordError
+// are called from every in-flight worker goroutine.
+type Reporter interface {
+	// IncInFlight marks one request as started; callers pair it with
+	// exactly one of RecordPayment or RecordError once it finishes.
+	IncInFlight()
+	RecordPayment(elapsed time.Duration)
+	RecordError(errorType RecordErrorType)
+	Snapshot() Snapshot
+}
+
+// Snapshot is a point-in-time view of everything a Reporter has seen so
+// far, cheap enough to take on every Prometheus scrape or summary tick.
+type Snapshot struct {
+	Ops          uint64
+	Errors       uint64
+	InFlight     int64
+	ErrorsByType map[RecordErrorType]uint64
+	Latency      LatencySummary
+	Since        time.Time
+}
+
+// OPS returns the cumulative operations per second since the reporter
+// was created.
+func (s Snapshot) OPS() float64 {
+	elapsed := time.Since(s.Since).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Ops) / elapsed
+}
+
+// ErrorRate returns the share of operations that ended in an error, in
+// the 0-1 range.
+func (s Snapshot) ErrorRate() float64 {
+	if s.Ops == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Ops)
+}
+
+// DefaultMetricsHistogramMax bounds the latency histogram a
+// MetricsReporter builds unless told otherwise, matching the `result`
+// command's own --histogram-max default.
+const DefaultMetricsHistogramMax = 10 * time.Second
+
+// MetricsReporter is the default Reporter: an in-memory counter set that
+// can be scraped over HTTP in Prometheus text format and summarized on a
+// fixed interval as a human-readable log line.
+type MetricsReporter struct {
+	sync.Mutex
+
+	ops          uint64
+	errs         uint64
+	inFlight     int64
+	errorsByType map[RecordErrorType]uint64
+	latency      *LatencyBuilder
+	since        time.Time
+}
+
+// NewMetricsReporter returns a ready-to-use MetricsReporter whose
+// counters start from zero as of now, bucketing latency up to
+// DefaultMetricsHistogramMax.
+func NewMetricsReporter() *MetricsReporter {
+	return NewMetricsReporterWithHistogramMax(DefaultMetricsHistogramMax)
+}
+
+// NewMetricsReporterWithHistogramMax is NewMetricsReporter, but with the
+// latency histogram's upper bound controlled by the caller.
+func NewMetricsReporterWithHistogramMax(histogramMax time.Duration) *MetricsReporter {
+	return &MetricsReporter{
+		errorsByType: map[RecordErrorType]uint64{},
+		latency:      NewLatencyBuilder(histogramMax, nil),
+		since:        time.Now(),
+	}
+}
+
+// IncInFlight marks one request as started.
+func (m *MetricsReporter) IncInFlight() {
+	atomic.AddInt64(&m.inFlight, 1)
+}
+
+func (m *MetricsReporter) RecordPayment(elapsed time.Duration) {
+	atomic.AddUint64(&m.ops, 1)
+	atomic.AddInt64(&m.inFlight, -1)
+
+	m.Lock()
+	defer m.Unlock()
+	m.latency.Observe(elapsed)
+}
+
+func (m *MetricsReporter) RecordError(errorType RecordErrorType) {
+	atomic.AddUint64(&m.ops, 1)
+	atomic.AddUint64(&m.errs, 1)
+	atomic.AddInt64(&m.inFlight, -1)
+
+	m.Lock()
+	defer m.Unlock()
+	m.errorsByType[errorType]++
+}
+
+func (m *MetricsReporter) Snapshot() Snapshot {
+	m.Lock()
+	defer m.Unlock()
+
+	byType := make(map[RecordErrorType]uint64, len(m.errorsByType))
+	for k, v := range m.errorsByType {
+		byType[k] = v
+	}
+
+	return Snapshot{
+		Ops:          atomic.LoadUint64(&m.ops),
+		Errors:       atomic.LoadUint64(&m.errs),
+		InFlight:     atomic.LoadInt64(&m.inFlight),
+		ErrorsByType: byType,
+		Latency:      m.latency.Summary(),
+		Since:        m.since,
+	}
+}
+
+// WritePrometheus renders the current snapshot in Prometheus text
+// exposition format.
+func (m *MetricsReporter) WritePrometheus(w http.ResponseWriter) {
+	snapshot := m.Snapshot()
+
+	fmt.Fprintf(w, "# HELP hotbody_ops_total total number of operations sent\n")
+	fmt.Fprintf(w, "# TYPE hotbody_ops_total counter\n")
+	fmt.Fprintf(w, "hotbody_ops_total %d\n", snapshot.Ops)
+
+	fmt.Fprintf(w, "# HELP hotbody_errors_total total number of failed operations\n")
+	fmt.Fprintf(w, "# TYPE hotbody_errors_total counter\n")
+	fmt.Fprintf(w, "hotbody_errors_total %d\n", snapshot.Errors)
+
+	fmt.Fprintf(w, "# HELP hotbody_errors_by_type_total failed operations broken down by error type\n")
+	fmt.Fprintf(w, "# TYPE hotbody_errors_by_type_total counter\n")
+	for errorType, count := range snapshot.ErrorsByType {
+		t := string(errorType)
+		if len(t) < 1 {
+			t = "unknown"
+		}
+		fmt.Fprintf(w, "hotbody_errors_by_type_total{type=%q} %d\n", t, count)
+	}
+
+	fmt.Fprintf(w, "# HELP hotbody_ops_per_second current cumulative operations per second\n")
+	fmt.Fprintf(w, "# TYPE hotbody_ops_per_second gauge\n")
+	fmt.Fprintf(w, "hotbody_ops_per_second %.5f\n", snapshot.OPS())
+
+	fmt.Fprintf(w, "# HELP hotbody_in_flight number of requests sent but not yet resolved\n")
+	fmt.Fprintf(w, "# TYPE hotbody_in_flight gauge\n")
+	fmt.Fprintf(w, "hotbody_in_flight %d\n", snapshot.InFlight)
+
+	fmt.Fprintf(w, "# HELP hotbody_elapsed_seconds elapsed time of resolved operations\n")
+	fmt.Fprintf(w, "# TYPE hotbody_elapsed_seconds histogram\n")
+	var cumulative int64
+	for _, bucket := range snapshot.Latency.Histogram {
+		le := "+Inf"
+		if bucket.UpperBound != OverflowBound {
+			le = fmt.Sprintf("%.6f", bucket.UpperBound.Seconds())
+		}
+
+		// Prometheus histogram buckets are cumulative: le="x" must
+		// include every sample counted by a smaller le, not just the
+		// samples that landed in this particular band.
+		cumulative += bucket.Count
+		fmt.Fprintf(w, "hotbody_elapsed_seconds_bucket{le=%q} %d\n", le, cumulative)
+	}
+	fmt.Fprintf(w, "hotbody_elapsed_seconds_count %d\n", snapshot.Latency.Count)
+
+	for _, q := range []struct {
+		quantile string
+		value    time.Duration
+	}{
+		{"0.5", snapshot.Latency.P50},
+		{"0.9", snapshot.Latency.P90},
+		{"0.95", snapshot.Latency.P95},
+		{"0.99", snapshot.Latency.P99},
+		{"0.999", snapshot.Latency.P999},
+	} {
+		fmt.Fprintf(w, "hotbody_elapsed_seconds{quantile=%q} %.6f\n", q.quantile, q.value.Seconds())
+	}
+}
+
+// ListenAndServeMetrics serves the Prometheus endpoint at /metrics on
+// addr. It blocks, so callers run it in its own goroutine.
+func (m *MetricsReporter) ListenAndServeMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		m.WritePrometheus(w)
+	})
+
+	return http.ListenAndServe(addr, mux)
+}
+
+// SummaryLine formats the current snapshot as a single human-readable
+// progress line, e.g. "12.3k ops/sec, cumulative 3.2M ops, 1.20% error".
+func (m *MetricsReporter) SummaryLine() string {
+	snapshot := m.Snapshot()
+
+	return fmt.Sprintf(
+		"%s ops/sec, cumulative %s ops, %.2f%% error",
+		humanizeRate(snapshot.OPS()),
+		humanizeCount(snapshot.Ops),
+		snapshot.ErrorRate()*100,
+	)
+}
+
+// StartSummaryTicker logs SummaryLine to logf every interval until stop
+// is closed.
+func (m *MetricsReporter) StartSummaryTicker(interval time.Duration, logf func(string), stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				logf(m.SummaryLine())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func humanizeCount(n uint64) string {
+	switch {
+	case n >= 1000000:
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+func humanizeRate(f float64) string {
+	switch {
+	case f >= 1000000:
+		return fmt.Sprintf("%.1fM", f/1000000)
+	case f >= 1000:
+		return fmt.Sprintf("%.1fk", f/1000)
+	default:
+		return fmt.Sprintf("%.1f", f)
+	}
+}
@@ -0,0 +1,142 @@
+package hotbody
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// ScenarioOp is a single captured operation: who sent to whom, how much,
+// when it was sent relative to the run's start, and the nonce that made
+// it reproducible, so a scenario can be replayed bit-for-bit.
+type ScenarioOp struct {
+	Source string
+	Target string
+	Amount uint64
+	At     time.Duration // relative to run start
+	Nonce  uint64
+}
+
+// ScenarioWriter appends ScenarioOps to a compact binary trace as a run
+// produces them, so capturing a scenario adds no meaningful memory
+// overhead over the run itself.
+type ScenarioWriter struct {
+	w io.Writer
+}
+
+// NewScenarioWriter returns a ScenarioWriter that appends to w.
+func NewScenarioWriter(w io.Writer) *ScenarioWriter {
+	return &ScenarioWriter{w: w}
+}
+
+func writeString(w io.Writer, s string) error {
+	if err := binary.Write(w, binary.BigEndian, uint16(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+func readString(r io.Reader) (string, error) {
+	var l uint16
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		return "", err
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Write appends a single ScenarioOp to the trace.
+func (s *ScenarioWriter) Write(op ScenarioOp) error {
+	if err := writeString(s.w, op.Source); err != nil {
+		return err
+	}
+	if err := writeString(s.w, op.Target); err != nil {
+		return err
+	}
+	if err := binary.Write(s.w, binary.BigEndian, op.Amount); err != nil {
+		return err
+	}
+	if err := binary.Write(s.w, binary.BigEndian, int64(op.At)); err != nil {
+		return err
+	}
+	return binary.Write(s.w, binary.BigEndian, op.Nonce)
+}
+
+// ScenarioReader reads ScenarioOps back from a trace, in the order they
+// were captured.
+type ScenarioReader struct {
+	r io.Reader
+}
+
+// NewScenarioReader returns a ScenarioReader over r.
+func NewScenarioReader(r io.Reader) *ScenarioReader {
+	return &ScenarioReader{r: r}
+}
+
+// Read returns the next ScenarioOp, or io.EOF once the trace is
+// exhausted.
+func (s *ScenarioReader) Read() (op ScenarioOp, err error) {
+	if op.Source, err = readString(s.r); err != nil {
+		return
+	}
+	if op.Target, err = readString(s.r); err != nil {
+		return
+	}
+	if err = binary.Read(s.r, binary.BigEndian, &op.Amount); err != nil {
+		return
+	}
+
+	var at int64
+	if err = binary.Read(s.r, binary.BigEndian, &at); err != nil {
+		return
+	}
+	op.At = time.Duration(at)
+
+	err = binary.Read(s.r, binary.BigEndian, &op.Nonce)
+	return
+}
+
+// ReadAll drains the trace into a slice, for callers small enough not to
+// need streaming.
+func (s *ScenarioReader) ReadAll() ([]ScenarioOp, error) {
+	var ops []ScenarioOp
+	for {
+		op, err := s.Read()
+		if err == io.EOF {
+			return ops, nil
+		} else if err != nil {
+			return ops, err
+		}
+		ops = append(ops, op)
+	}
+}
+
+// Sender issues a single replayed operation against a live network. The
+// hotter's own payment client implements this; Replay only depends on
+// the interface so it can be driven without that client being present.
+type Sender interface {
+	Send(op ScenarioOp) error
+}
+
+// Replay re-issues every op in ops against sender, sleeping between
+// sends so each one lands at the same offset from start that it did in
+// the captured run.
+func Replay(ops []ScenarioOp, sender Sender) error {
+	start := time.Now()
+
+	for _, op := range ops {
+		if wait := op.At - time.Since(start); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		if err := sender.Send(op); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package hotbody
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScenarioWriterReaderRoundTrip(t *testing.T) {
+	ops := []ScenarioOp{
+		{Source: "ac1", Target: "ac2", Amount: 100, At: 0, Nonce: 1},
+		{Source: "ac2", Target: "ac3", Amount: 250, At: 10 * time.Millisecond, Nonce: 2},
+		{Source: "ac3", Target: "ac1", Amount: 0, At: 2 * time.Second, Nonce: 3},
+	}
+
+	var buf bytes.Buffer
+	w := NewScenarioWriter(&buf)
+	for _, op := range ops {
+		if err := w.Write(op); err != nil {
+			t.Fatalf("Write(%+v) failed; %v", op, err)
+		}
+	}
+
+	got, err := NewScenarioReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() failed; %v", err)
+	}
+
+	if !reflect.DeepEqual(got, ops) {
+		t.Fatalf("round-tripped ops = %+v, want %+v", got, ops)
+	}
+}
+
+func TestScenarioReaderReadAllEmpty(t *testing.T) {
+	ops, err := NewScenarioReader(&bytes.Buffer{}).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() on empty trace failed; %v", err)
+	}
+	if len(ops) != 0 {
+		t.Fatalf("ReadAll() on empty trace = %+v, want empty", ops)
+	}
+}
+
+type recordingSender struct {
+	sent []ScenarioOp
+}
+
+func (s *recordingSender) Send(op ScenarioOp) error {
+	s.sent = append(s.sent, op)
+	return nil
+}
+
+func TestReplaySendsEveryOp(t *testing.T) {
+	ops := []ScenarioOp{
+		{Source: "ac1", Target: "ac2", Amount: 1, At: 0, Nonce: 1},
+		{Source: "ac2", Target: "ac3", Amount: 2, At: time.Millisecond, Nonce: 2},
+	}
+
+	sender := &recordingSender{}
+	if err := Replay(ops, sender); err != nil {
+		t.Fatalf("Replay() failed; %v", err)
+	}
+
+	if !reflect.DeepEqual(sender.sent, ops) {
+		t.Fatalf("sent ops = %+v, want %+v", sender.sent, ops)
+	}
+}
@@ -0,0 +1,68 @@
+package hotbody
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WorkerClient talks to a Coordinator's HTTP endpoints on behalf of a
+// single worker process.
+type WorkerClient struct {
+	CoordinatorAddr string
+	ID              string
+}
+
+// Register registers this worker with the coordinator and returns the
+// account range and target OPS it was assigned.
+func (w *WorkerClient) Register() (assignment WorkerAssignment, err error) {
+	body, err := json.Marshal(WorkerRegistration{ID: w.ID})
+	if err != nil {
+		return
+	}
+
+	resp, err := http.Post(w.CoordinatorAddr+"/register", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("coordinator returned %s registering %q", resp.Status, w.ID)
+		return
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&assignment)
+	return
+}
+
+// SendRecords streams a batch of Records back to the coordinator to be
+// merged into the unified result log.
+func (w *WorkerClient) SendRecords(records []Record) error {
+	raw := make([]json.RawMessage, len(records))
+	for i, r := range records {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		raw[i] = b
+	}
+
+	body, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(w.CoordinatorAddr+"/records", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("coordinator returned %s ingesting records from %q", resp.Status, w.ID)
+	}
+
+	return nil
+}
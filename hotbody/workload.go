@@ -0,0 +1,198 @@
+package hotbody
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OperationMix is one weighted entry of a WorkloadProfile's transaction
+// mix, e.g. "90% single payments, 10% 10-op batched payments".
+type OperationMix struct {
+	Kind      string  `json:"kind" yaml:"kind"`             // "payment", "create-account", "payment-batch", ...
+	Weight    float64 `json:"weight" yaml:"weight"`
+	BatchSize int     `json:"batch_size,omitempty" yaml:"batch_size,omitempty"`
+}
+
+// InterArrival describes how the hotter should space out requests.
+type InterArrival struct {
+	Type     string  `json:"type" yaml:"type"` // "constant", "poisson", "on-off"
+	Rate     float64 `json:"rate" yaml:"rate"` // requests/sec
+	BurstOn  float64 `json:"burst_on,omitempty" yaml:"burst_on,omitempty"`   // seconds, "on-off" only
+	BurstOff float64 `json:"burst_off,omitempty" yaml:"burst_off,omitempty"` // seconds, "on-off" only
+}
+
+// defaultZipfianExponent is used whenever a zipfian AccountSkew doesn't
+// specify one, since rand.NewZipf requires an exponent strictly greater
+// than 1.
+const defaultZipfianExponent = 1.5
+
+// AccountSkew describes how source accounts are picked from the pool;
+// the zero value is uniform.
+type AccountSkew struct {
+	Distribution string  `json:"distribution,omitempty" yaml:"distribution,omitempty"` // "" or "zipfian"
+	Exponent     float64 `json:"exponent,omitempty" yaml:"exponent,omitempty"`
+}
+
+// WorkloadProfile is the user-supplied description of a non-uniform
+// transaction mix: what kinds of operations to send, how often, and
+// which accounts to skew towards.
+type WorkloadProfile struct {
+	Mix          []OperationMix `json:"mix" yaml:"mix"`
+	InterArrival InterArrival   `json:"inter_arrival" yaml:"inter_arrival"`
+	AccountSkew  AccountSkew    `json:"account_skew,omitempty" yaml:"account_skew,omitempty"`
+}
+
+// LoadWorkloadProfile reads a WorkloadProfile from path, dispatching on
+// its extension: ".yaml"/".yml" is parsed as YAML, everything else as
+// JSON.
+func LoadWorkloadProfile(path string) (profile WorkloadProfile, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &profile)
+	default:
+		err = json.Unmarshal(b, &profile)
+	}
+	if err != nil {
+		return
+	}
+
+	err = profile.Validate()
+	return
+}
+
+// Validate returns an error if the profile can't be sampled from, e.g.
+// an empty mix or a non-positive weight.
+func (p WorkloadProfile) Validate() error {
+	if len(p.Mix) < 1 {
+		return fmt.Errorf("workload profile has no operation mix")
+	}
+
+	var total float64
+	for _, m := range p.Mix {
+		if m.Weight <= 0 {
+			return fmt.Errorf("operation %q has non-positive weight %v", m.Kind, m.Weight)
+		}
+		total += m.Weight
+	}
+	if total <= 0 {
+		return fmt.Errorf("workload profile's weights sum to %v", total)
+	}
+
+	if p.AccountSkew.Distribution == "zipfian" && p.AccountSkew.Exponent != 0 && p.AccountSkew.Exponent <= 1 {
+		return fmt.Errorf("zipfian account skew requires exponent > 1, got %v", p.AccountSkew.Exponent)
+	}
+
+	return nil
+}
+
+// WorkloadSampler draws operation kinds, inter-arrival gaps and account
+// indices from a WorkloadProfile.
+type WorkloadSampler struct {
+	profile   WorkloadProfile
+	totalW    float64
+	burstOn   bool
+	burstLeft float64
+	rand      *rand.Rand
+}
+
+// NewWorkloadSampler returns a WorkloadSampler over profile, seeded from
+// seed so a run is reproducible.
+func NewWorkloadSampler(profile WorkloadProfile, seed int64) *WorkloadSampler {
+	var total float64
+	for _, m := range profile.Mix {
+		total += m.Weight
+	}
+
+	return &WorkloadSampler{
+		profile:   profile,
+		totalW:    total,
+		burstOn:   true,
+		burstLeft: profile.InterArrival.BurstOn,
+		rand:      rand.New(rand.NewSource(seed)),
+	}
+}
+
+// NextOperation draws a weighted-random entry from the profile's mix.
+func (s *WorkloadSampler) NextOperation() OperationMix {
+	r := s.rand.Float64() * s.totalW
+
+	var cum float64
+	for _, m := range s.profile.Mix {
+		cum += m.Weight
+		if r <= cum {
+			return m
+		}
+	}
+
+	return s.profile.Mix[len(s.profile.Mix)-1]
+}
+
+// NextInterArrival returns how long to wait before the next request, in
+// seconds, per the profile's InterArrival policy.
+func (s *WorkloadSampler) NextInterArrival() float64 {
+	ia := s.profile.InterArrival
+	if ia.Rate <= 0 {
+		return 0
+	}
+
+	mean := 1 / ia.Rate
+
+	switch ia.Type {
+	case "poisson":
+		return -mean * math.Log(1-s.rand.Float64())
+	case "on-off":
+		if s.burstOn {
+			wait := mean
+			s.burstLeft -= mean
+			if s.burstLeft <= 0 {
+				s.burstOn = false
+				s.burstLeft = ia.BurstOff
+			}
+			return wait
+		}
+
+		// The off phase sends nothing: collapse it into a single wait
+		// spanning the whole remaining off-duration, then resume the
+		// on phase's normal cadence.
+		wait := s.burstLeft
+		if wait <= 0 {
+			wait = ia.BurstOff
+		}
+		s.burstOn = true
+		s.burstLeft = ia.BurstOn
+		return wait
+	default: // "constant"
+		return mean
+	}
+}
+
+// NextAccountIndex draws an index in [0,n) from the profile's
+// AccountSkew, defaulting to uniform.
+func (s *WorkloadSampler) NextAccountIndex(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if s.profile.AccountSkew.Distribution != "zipfian" {
+		return s.rand.Intn(n)
+	}
+
+	exponent := s.profile.AccountSkew.Exponent
+	if exponent <= 1 {
+		exponent = defaultZipfianExponent
+	}
+
+	z := rand.NewZipf(s.rand, exponent, 1, uint64(n-1))
+	return int(z.Uint64())
+}
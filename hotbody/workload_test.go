@@ -0,0 +1,192 @@
+package hotbody
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWorkloadProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile WorkloadProfile
+		wantErr bool
+	}{
+		{
+			name:    "empty mix",
+			profile: WorkloadProfile{},
+			wantErr: true,
+		},
+		{
+			name: "non-positive weight",
+			profile: WorkloadProfile{
+				Mix: []OperationMix{{Kind: "payment", Weight: 0}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zipfian exponent too small",
+			profile: WorkloadProfile{
+				Mix:         []OperationMix{{Kind: "payment", Weight: 1}},
+				AccountSkew: AccountSkew{Distribution: "zipfian", Exponent: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zipfian default exponent is valid",
+			profile: WorkloadProfile{
+				Mix:         []OperationMix{{Kind: "payment", Weight: 1}},
+				AccountSkew: AccountSkew{Distribution: "zipfian"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid uniform mix",
+			profile: WorkloadProfile{
+				Mix: []OperationMix{{Kind: "payment", Weight: 9}, {Kind: "create-account", Weight: 1}},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkloadSamplerNextOperationWeighting(t *testing.T) {
+	profile := WorkloadProfile{
+		Mix: []OperationMix{
+			{Kind: "payment", Weight: 9},
+			{Kind: "create-account", Weight: 1},
+		},
+	}
+	sampler := NewWorkloadSampler(profile, 1)
+
+	const n = 10000
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[sampler.NextOperation().Kind]++
+	}
+
+	// With a 9:1 weighting, "payment" should dominate by a wide, if not
+	// exact, margin.
+	if counts["payment"] <= counts["create-account"]*3 {
+		t.Errorf("counts = %+v, want payment to heavily outweigh create-account", counts)
+	}
+}
+
+func TestWorkloadSamplerNextAccountIndex(t *testing.T) {
+	t.Run("uniform stays in range", func(t *testing.T) {
+		sampler := NewWorkloadSampler(WorkloadProfile{Mix: []OperationMix{{Kind: "payment", Weight: 1}}}, 1)
+		for i := 0; i < 1000; i++ {
+			if idx := sampler.NextAccountIndex(10); idx < 0 || idx >= 10 {
+				t.Fatalf("NextAccountIndex(10) = %d, want [0,10)", idx)
+			}
+		}
+	})
+
+	t.Run("zipfian stays in range", func(t *testing.T) {
+		profile := WorkloadProfile{
+			Mix:         []OperationMix{{Kind: "payment", Weight: 1}},
+			AccountSkew: AccountSkew{Distribution: "zipfian", Exponent: 1.5},
+		}
+		sampler := NewWorkloadSampler(profile, 1)
+		for i := 0; i < 1000; i++ {
+			if idx := sampler.NextAccountIndex(10); idx < 0 || idx >= 10 {
+				t.Fatalf("NextAccountIndex(10) = %d, want [0,10)", idx)
+			}
+		}
+	})
+
+	t.Run("zipfian without an explicit exponent doesn't panic", func(t *testing.T) {
+		profile := WorkloadProfile{
+			Mix:         []OperationMix{{Kind: "payment", Weight: 1}},
+			AccountSkew: AccountSkew{Distribution: "zipfian"},
+		}
+		sampler := NewWorkloadSampler(profile, 1)
+		if idx := sampler.NextAccountIndex(10); idx < 0 || idx >= 10 {
+			t.Fatalf("NextAccountIndex(10) = %d, want [0,10)", idx)
+		}
+	})
+
+	t.Run("n<=0 returns 0", func(t *testing.T) {
+		sampler := NewWorkloadSampler(WorkloadProfile{Mix: []OperationMix{{Kind: "payment", Weight: 1}}}, 1)
+		if idx := sampler.NextAccountIndex(0); idx != 0 {
+			t.Errorf("NextAccountIndex(0) = %d, want 0", idx)
+		}
+	})
+}
+
+func TestWorkloadSamplerNextInterArrivalOnOff(t *testing.T) {
+	profile := WorkloadProfile{
+		Mix: []OperationMix{{Kind: "payment", Weight: 1}},
+		InterArrival: InterArrival{
+			Type:     "on-off",
+			Rate:     10,
+			BurstOn:  0.5,
+			BurstOff: 2,
+		},
+	}
+	sampler := NewWorkloadSampler(profile, 1)
+
+	mean := 1 / profile.InterArrival.Rate
+	const epsilon = 1e-9
+
+	// Drain one full on-phase: gaps of ~mean accumulating to ~BurstOn,
+	// followed by exactly one larger gap (the collapsed off-phase).
+	var onTotal float64
+	var onCount int
+	var offGap float64
+	for i := 0; i < 1000; i++ {
+		gap := sampler.NextInterArrival()
+		if gap > mean+epsilon {
+			offGap = gap
+			break
+		}
+		onTotal += gap
+		onCount++
+	}
+
+	if onCount < 1 {
+		t.Fatal("on-phase produced no gaps before switching off")
+	}
+	if math.Abs(onTotal-profile.InterArrival.BurstOn) > mean {
+		t.Errorf("on-phase gaps summed to %v, want close to BurstOn=%v", onTotal, profile.InterArrival.BurstOn)
+	}
+	if math.Abs(offGap-profile.InterArrival.BurstOff) > epsilon {
+		t.Errorf("off-phase gap = %v, want a single gap of BurstOff=%v", offGap, profile.InterArrival.BurstOff)
+	}
+
+	// The sampler must resume the on phase immediately afterwards, with
+	// no further off-phase gaps trickled in.
+	if next := sampler.NextInterArrival(); next != mean {
+		t.Errorf("gap after off-phase = %v, want resumed on-phase gap %v", next, mean)
+	}
+}
+
+func TestWorkloadSamplerNextInterArrivalConstant(t *testing.T) {
+	profile := WorkloadProfile{
+		Mix:          []OperationMix{{Kind: "payment", Weight: 1}},
+		InterArrival: InterArrival{Type: "constant", Rate: 5},
+	}
+	sampler := NewWorkloadSampler(profile, 1)
+
+	want := 1 / profile.InterArrival.Rate
+	for i := 0; i < 10; i++ {
+		if got := sampler.NextInterArrival(); got != want {
+			t.Errorf("NextInterArrival() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWorkloadSamplerNextInterArrivalNoRate(t *testing.T) {
+	sampler := NewWorkloadSampler(WorkloadProfile{Mix: []OperationMix{{Kind: "payment", Weight: 1}}}, 1)
+	if got := sampler.NextInterArrival(); got != 0 {
+		t.Errorf("NextInterArrival() with no rate = %v, want 0", got)
+	}
+}